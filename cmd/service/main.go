@@ -5,12 +5,20 @@ import (
 	"log"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go-service/internal/app"
 	"go-service/internal/service"
+	"go-service/internal/service/outbox"
+	"go-service/internal/service/repository"
 	"go-service/pkg/service/server"
 )
 
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 100
+)
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -27,6 +35,10 @@ func main() {
 
 	server.RegisterServiceServer(application.GRPC(), service.New(application))
 
+	repo := repository.New(application.DB())
+	sink := outbox.NewStdoutSink(application.Logger())
+	application.AddComponent(outbox.NewPoller(repo, sink, application.Logger(), outboxPollInterval, outboxBatchSize))
+
 	if err = application.Run(); err != nil {
 		log.Fatalf("application terminated abnormally: %s", err)
 	}
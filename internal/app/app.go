@@ -2,30 +2,56 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/net/netutil"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+
+	"go-service/internal/app/health"
 )
 
 type backgroundJob func(context.Context) error
 
 func New(ctx context.Context, cfg Config) (*App, error) {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create logger")
+	}
+
 	app := &App{
-		ctx: ctx,
-		cfg: cfg,
+		ctx:       ctx,
+		logger:    logger,
+		cfg:       cfg,
+		readiness: make(map[string]*health.Readiness),
+	}
+	if err := app.registerGRPCServer(); err != nil {
+		return nil, err
+	}
+	if err := app.registerGatewayServer(); err != nil {
+		return nil, err
+	}
+	if err := app.registerHTTPServer(); err != nil {
+		return nil, err
 	}
-	app.registerGRPCServer()
-	app.registerHTTPServer()
 	return app, nil
 }
 
@@ -35,35 +61,85 @@ type App struct {
 	db     *sqlx.DB
 	cfg    Config
 	grpc   *grpc.Server
-	jobs   []backgroundJob
+
+	components []Component
+
+	grpcHealth  *grpchealth.Server
+	readiness   map[string]*health.Readiness
+	readinessMu sync.Mutex
+
+	grpcListener net.Listener
+	gatewayConn  *grpc.ClientConn
+	gatewayMux   *runtime.ServeMux
+
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	unaryChainOnce     sync.Once
+	unaryChain         grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	streamChainOnce    sync.Once
+	streamChain        grpc.StreamServerInterceptor
 }
 
+// Run starts every registered Component in registration order and blocks
+// until the application context is cancelled or a component fails. It then
+// stops components in reverse order, bounded by Config.ShutdownTimeout, and
+// returns the combined start and stop errors.
 func (app *App) Run() error {
+	// Registered here, rather than in registerGRPCServer, so the per-method
+	// metrics are initialized for every service registered between New and
+	// Run instead of only the ones that happened to exist at New time.
+	grpc_prometheus.EnableHandlingTimeHistogram()
+	grpc_prometheus.Register(app.grpc)
+
 	app.Logger().Info("started application")
 
+	runCtx, cancelRun := context.WithCancel(app.ctx)
+	defer cancelRun()
+
 	var wg sync.WaitGroup
-	errChannel := make(chan error, len(app.jobs))
+	errChannel := make(chan error, len(app.components))
 
-	for _, job := range app.jobs {
+	for _, component := range app.components {
 		wg.Add(1)
-		go func(job backgroundJob) {
+		go func(component Component) {
 			defer wg.Done()
-			errChannel <- job(app.ctx)
-		}(job)
+			if err := component.Start(runCtx); err != nil {
+				errChannel <- errors.Wrapf(err, "%s", component.Name())
+			}
+		}(component)
 	}
 
+	var runErr error
 	select {
 	case <-app.ctx.Done():
-		wg.Wait()
-		close(errChannel)
-		errs := make([]error, len(errChannel))
-		for err := range errChannel {
-			errs = append(errs, err)
+	case runErr = <-errChannel:
+	}
+	// Components such as the outbox poller or the gateway connection closer
+	// only return from Start when their context is cancelled. On the
+	// app.ctx.Done() path this happens automatically since runCtx derives
+	// from app.ctx; on the runErr path nothing else would ever cancel it, so
+	// cancel explicitly here or wg.Wait below hangs forever.
+	cancelRun()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.cfg.ShutdownTimeout)
+	defer cancel()
+
+	var stopErrs []error
+	for i := len(app.components) - 1; i >= 0; i-- {
+		component := app.components[i]
+		app.Logger().Info("stopping component", zap.String("component", component.Name()))
+		if err := component.Stop(stopCtx); err != nil {
+			stopErrs = append(stopErrs, errors.Wrapf(err, "could not stop %s", component.Name()))
 		}
-		return multierr.Combine(errs...)
-	case err := <-errChannel:
-		return err
 	}
+
+	wg.Wait()
+	close(errChannel)
+	for err := range errChannel {
+		stopErrs = append(stopErrs, err)
+	}
+
+	return multierr.Combine(append([]error{runErr}, stopErrs...)...)
 }
 
 func (app *App) Error(code codes.Code, err error, fields ...zap.Field) error {
@@ -89,12 +165,40 @@ func (app *App) Error(code codes.Code, err error, fields ...zap.Field) error {
 	return status.Error(code, msg)
 }
 
-func (app *App) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+// RegisterService registers a gRPC service implementation. When name is
+// given, the service also gets its own entry in the gRPC health protocol
+// (grpc.health.v1.Health) and the /health endpoint, reported through the
+// returned Readiness probe, which the caller can toggle with Ready/NotReady
+// as the service's dependencies come up or down.
+func (app *App) RegisterService(desc *grpc.ServiceDesc, impl interface{}, name ...string) *health.Readiness {
 	app.grpc.RegisterService(desc, impl)
+	if len(name) == 0 {
+		return nil
+	}
+
+	serviceName := name[0]
+	app.grpcHealth.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	probe := health.NewReadiness()
+	probe.OnChange(func(ready bool) {
+		status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		if ready {
+			status = grpc_health_v1.HealthCheckResponse_SERVING
+		}
+		app.grpcHealth.SetServingStatus(serviceName, status)
+	})
+	app.AddReadinessProbe(serviceName, probe)
+
+	return probe
 }
 
-func (app *App) AddBackgroundJob(job backgroundJob) {
-	app.jobs = append(app.jobs, job)
+// AddReadinessProbe registers a readiness probe under name so its state is
+// reflected in the /health endpoint. Probes for services registered via
+// RegisterService are added automatically.
+func (app *App) AddReadinessProbe(name string, probe *health.Readiness) {
+	app.readinessMu.Lock()
+	defer app.readinessMu.Unlock()
+	app.readiness[name] = probe
 }
 
 func (app *App) Logger() *zap.Logger {
@@ -109,44 +213,261 @@ func (app *App) GRPC() *grpc.Server {
 	return app.grpc
 }
 
-func (app *App) registerGRPCServer() {
-	app.grpc = grpc.NewServer()
-	app.AddBackgroundJob(func(ctx context.Context) error {
-		listener, listenErr := net.Listen("tcp", app.cfg.GRPCPort)
-		if listenErr != nil {
-			return errors.Wrap(listenErr, "could not open GRPC port to serve")
-		}
-		app.Logger().Info("starting GRPC server")
-		return errors.Wrap(app.grpc.Serve(listener), "GRPC server error")
+// registerGRPCServer builds the gRPC server and binds its listener
+// synchronously, so that a port conflict surfaces immediately from New
+// instead of racing ctx.Done() in a background goroutine, then registers it
+// as a Component so Run starts and stops it in order with everything else.
+func (app *App) registerGRPCServer() error {
+	opts, err := app.grpcServerOptions()
+	if err != nil {
+		return err
+	}
+	app.grpc = grpc.NewServer(opts...)
+
+	if app.cfg.EnableReflection {
+		reflection.Register(app.grpc)
+	}
+
+	app.grpcHealth = grpchealth.NewServer()
+	app.grpcHealth.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(app.grpc, app.grpcHealth)
+
+	listener, err := net.Listen("tcp", app.cfg.GRPCPort)
+	if err != nil {
+		return errors.Wrap(err, "could not open GRPC port to serve")
+	}
+	if app.cfg.MaxConnections > 0 {
+		listener = netutil.LimitListener(listener, app.cfg.MaxConnections)
+	}
+	app.grpcListener = listener
+
+	app.AddComponent(&grpcServerComponent{
+		logger:   app.logger,
+		server:   app.grpc,
+		listener: listener,
 	})
+	return nil
+}
+
+// grpcServerOptions translates Config into grpc.ServerOptions: keepalive
+// parameters, message size and concurrent stream limits, and TLS credentials
+// (falling back to insecure only when no certificate is configured).
+func (app *App) grpcServerOptions() ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(app.unaryInterceptor),
+		grpc.ChainStreamInterceptor(app.streamInterceptor),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    app.cfg.KeepaliveTime,
+			Timeout: app.cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             app.cfg.KeepaliveMinTime,
+			PermitWithoutStream: app.cfg.KeepalivePermitWithoutStream,
+		}),
+	}
+
+	if app.cfg.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(app.cfg.MaxConcurrentStreams))
+	}
+	if app.cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(app.cfg.MaxRecvMsgSize))
+	}
+	if app.cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(app.cfg.MaxSendMsgSize))
+	}
+
+	creds, err := app.cfg.tlsCredentials()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load TLS credentials")
+	}
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	return opts, nil
+}
+
+// registerGatewayServer dials the local gRPC server over loopback and sets up
+// a grpc-gateway ServeMux that RegisterServiceWithGateway adds handlers to.
+// The mux is mounted onto the HTTP server by registerHTTPServer, so every
+// service registered through the gateway is reachable as JSON/HTTP without a
+// second process. The dial uses tlsClientCredentials rather than always
+// dialing insecurely, so the handshake still succeeds once the GRPC server
+// is configured for TLS or mTLS.
+func (app *App) registerGatewayServer() error {
+	creds, err := app.cfg.tlsClientCredentials()
+	if err != nil {
+		return errors.Wrap(err, "could not load TLS credentials for gateway dial")
+	}
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+
+	conn, err := grpc.DialContext(app.ctx, app.grpcListener.Addr().String(),
+		grpc.WithTransportCredentials(creds),
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not dial local GRPC server for gateway")
+	}
+	app.gatewayConn = conn
+
+	app.gatewayMux = runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+	)
+
 	app.AddBackgroundJob(func(ctx context.Context) error {
 		<-ctx.Done()
-		app.grpc.GracefulStop()
-		return nil
+		return conn.Close()
 	})
+	return nil
+}
+
+// gatewayHeaderMatcher forwards the standard headers grpc-gateway drops by
+// default, in addition to the usual Grpc-Metadata-* ones, as gRPC metadata.
+func gatewayHeaderMatcher(header string) (string, bool) {
+	switch strings.ToLower(header) {
+	case "authorization", "x-request-id":
+		return header, true
+	}
+	return runtime.DefaultHeaderMatcher(header)
+}
+
+// RegisterServiceWithGateway registers a gRPC service implementation, as
+// RegisterService does, and additionally wires its grpc-gateway handlers
+// (generated alongside the service's *_grpc.pb.go as *_gw.pb.go) onto the
+// HTTP server under Config.GatewayPathPrefix.
+func (app *App) RegisterServiceWithGateway(
+	desc *grpc.ServiceDesc,
+	impl interface{},
+	gwRegister func(context.Context, *runtime.ServeMux, *grpc.ClientConn) error,
+	name ...string,
+) (*health.Readiness, error) {
+	probe := app.RegisterService(desc, impl, name...)
+	if err := gwRegister(app.ctx, app.gatewayMux, app.gatewayConn); err != nil {
+		return nil, errors.Wrap(err, "could not register gateway handlers")
+	}
+	return probe, nil
 }
 
-func (app *App) registerHTTPServer() {
+// registerHTTPServer builds the HTTP server and binds its listener
+// synchronously, for the same reason as registerGRPCServer, then registers it
+// as a Component.
+func (app *App) registerHTTPServer() error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		if err := app.db.Ping(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-	})
+	mux.HandleFunc("/health", app.handleHealth)
+	mux.HandleFunc("/live", app.handleLive)
+
+	prefix := strings.TrimSuffix(app.cfg.GatewayPathPrefix, "/")
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, app.gatewayMux))
+
 	httpServer := &http.Server{
 		Handler: mux,
-		Addr:    app.cfg.HTTPPort,
 	}
-	app.AddBackgroundJob(func(ctx context.Context) error {
-		app.Logger().Info("starting HTTP server")
-		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-			return err
+
+	listener, err := net.Listen("tcp", app.cfg.HTTPPort)
+	if err != nil {
+		return errors.Wrap(err, "could not open HTTP port to serve")
+	}
+
+	app.AddComponent(&httpServerComponent{
+		logger:   app.logger,
+		server:   httpServer,
+		listener: listener,
+	})
+	return nil
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// handleHealth aggregates the DB ping and every registered readiness probe
+// into a single JSON status body, for use as a Kubernetes readiness probe.
+func (app *App) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{Status: "ok", Checks: make(map[string]string)}
+
+	if err := app.db.PingContext(r.Context()); err != nil {
+		resp.Checks["db"] = err.Error()
+		resp.Status = "unavailable"
+	} else {
+		resp.Checks["db"] = "ok"
+	}
+
+	app.readinessMu.Lock()
+	for name, probe := range app.readiness {
+		if probe.IsReady() {
+			resp.Checks[name] = "ok"
+		} else {
+			resp.Checks[name] = "not ready"
+			resp.Status = "unavailable"
 		}
+	}
+	app.readinessMu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleLive only checks that the process is alive, independent of
+// readiness, for use as a Kubernetes liveness probe.
+func (app *App) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// grpcServerComponent is the first-class Component wrapping the built-in
+// gRPC server.
+type grpcServerComponent struct {
+	logger   *zap.Logger
+	server   *grpc.Server
+	listener net.Listener
+}
+
+func (c *grpcServerComponent) Name() string { return "grpc-server" }
+
+func (c *grpcServerComponent) Start(ctx context.Context) error {
+	c.logger.Info("starting GRPC server")
+	return errors.Wrap(c.server.Serve(c.listener), "GRPC server error")
+}
+
+func (c *grpcServerComponent) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		c.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
 		return nil
-	})
-	app.AddBackgroundJob(func(ctx context.Context) error {
-		<-ctx.Done()
-		return httpServer.Shutdown(context.Background())
-	})
+	case <-ctx.Done():
+		c.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// httpServerComponent is the first-class Component wrapping the built-in
+// HTTP server.
+type httpServerComponent struct {
+	logger   *zap.Logger
+	server   *http.Server
+	listener net.Listener
+}
+
+func (c *httpServerComponent) Name() string { return "http-server" }
+
+func (c *httpServerComponent) Start(ctx context.Context) error {
+	c.logger.Info("starting HTTP server")
+	if err := c.server.Serve(c.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (c *httpServerComponent) Stop(ctx context.Context) error {
+	return c.server.Shutdown(ctx)
 }
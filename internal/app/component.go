@@ -0,0 +1,51 @@
+package app
+
+import "context"
+
+// Component is a named, independently startable and stoppable part of the
+// application lifecycle (a server, a poller, a connection pool, ...). App
+// starts components in registration order and stops them in reverse order,
+// under Config.ShutdownTimeout, so teardown happens in the opposite order of
+// setup and each step is logged by name.
+type Component interface {
+	// Name identifies the component in lifecycle logs.
+	Name() string
+	// Start runs the component for as long as the application is up. It
+	// should block until either it fails or Stop causes it to return, and
+	// must return nil on a clean shutdown.
+	Start(ctx context.Context) error
+	// Stop asks the component to shut down, respecting ctx's deadline.
+	Stop(ctx context.Context) error
+}
+
+// AddComponent registers a Component to be started, in registration order,
+// when Run is called, and stopped in reverse order on shutdown.
+func (app *App) AddComponent(component Component) {
+	app.components = append(app.components, component)
+}
+
+// backgroundJobComponent adapts the legacy backgroundJob signature to the
+// Component interface for AddBackgroundJob. The job is expected to watch
+// ctx.Done() itself to know when to return, so Stop has nothing to do and
+// returns nil at once; Run waits for the job's Start to return.
+type backgroundJobComponent struct {
+	job backgroundJob
+}
+
+func (c *backgroundJobComponent) Name() string { return "background-job" }
+
+func (c *backgroundJobComponent) Start(ctx context.Context) error {
+	return c.job(ctx)
+}
+
+func (c *backgroundJobComponent) Stop(context.Context) error {
+	return nil
+}
+
+// AddBackgroundJob registers job as a Component that runs for the lifetime of
+// the app. It is kept as a thin adapter over AddComponent for callers that
+// don't need an explicit Start/Stop split and instead watch ctx.Done()
+// themselves, as the original background jobs did.
+func (app *App) AddBackgroundJob(job backgroundJob) {
+	app.AddComponent(&backgroundJobComponent{job: job})
+}
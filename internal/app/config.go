@@ -1,12 +1,43 @@
 package app
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	GRPCPort string `envconfig:"GRPC_PORT" default:":9090"`
 	HTTPPort string `envconfig:"HTTP_PORT" default:":8080"`
+
+	GatewayPathPrefix string `envconfig:"GATEWAY_PATH_PREFIX" default:"/api"`
+
+	EnableReflection bool `envconfig:"ENABLE_REFLECTION" default:"false"`
+
+	// TLSCertFile and TLSKeyFile enable TLS on the GRPC server when both are
+	// set. TLSClientCAFile additionally enables mTLS, requiring and
+	// verifying a client certificate signed by that CA.
+	TLSCertFile     string `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile      string `envconfig:"TLS_KEY_FILE"`
+	TLSClientCAFile string `envconfig:"TLS_CLIENT_CA_FILE"`
+
+	KeepaliveTime                time.Duration `envconfig:"KEEPALIVE_TIME" default:"2h"`
+	KeepaliveTimeout             time.Duration `envconfig:"KEEPALIVE_TIMEOUT" default:"20s"`
+	KeepaliveMinTime             time.Duration `envconfig:"KEEPALIVE_MIN_TIME" default:"5m"`
+	KeepalivePermitWithoutStream bool          `envconfig:"KEEPALIVE_PERMIT_WITHOUT_STREAM" default:"false"`
+
+	// MaxConcurrentStreams, MaxRecvMsgSize and MaxSendMsgSize are left at the
+	// GRPC defaults when zero.
+	MaxConcurrentStreams uint32 `envconfig:"MAX_CONCURRENT_STREAMS" default:"0"`
+	MaxRecvMsgSize       int    `envconfig:"MAX_RECV_MSG_SIZE" default:"0"`
+	MaxSendMsgSize       int    `envconfig:"MAX_SEND_MSG_SIZE" default:"0"`
+
+	// MaxConnections caps the number of concurrent GRPC client connections.
+	// Zero means unlimited.
+	MaxConnections int `envconfig:"MAX_CONNECTIONS" default:"0"`
+
+	// ShutdownTimeout bounds how long Run waits for components to stop.
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
 }
 
 func NewConfigFromEnv() (cfg Config, err error) {
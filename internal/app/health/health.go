@@ -0,0 +1,53 @@
+// Package health provides a small readiness-probe primitive used by App and
+// its registered services to report whether they are ready to serve traffic.
+package health
+
+import "sync/atomic"
+
+// Readiness is a concurrency-safe readiness toggle. Components create one,
+// flip it with Ready/NotReady as their dependencies come up or down, and
+// register it with App so its state is reflected on /health and, for named
+// gRPC services, in the gRPC health protocol.
+type Readiness struct {
+	ready    int32
+	onChange func(ready bool)
+}
+
+// NewReadiness returns a Readiness probe that starts out not ready.
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// Ready marks the probe as ready.
+func (r *Readiness) Ready() {
+	r.setReady(true)
+}
+
+// NotReady marks the probe as not ready.
+func (r *Readiness) NotReady() {
+	r.setReady(false)
+}
+
+// IsReady reports the probe's current state.
+func (r *Readiness) IsReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// OnChange installs a callback invoked whenever the probe's state changes via
+// Ready/NotReady. It exists for infrastructure that needs to mirror the
+// probe's state elsewhere (e.g. the gRPC health service) and must be called
+// before the probe is shared with other goroutines.
+func (r *Readiness) OnChange(fn func(ready bool)) {
+	r.onChange = fn
+}
+
+func (r *Readiness) setReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&r.ready, v)
+	if r.onChange != nil {
+		r.onChange(ready)
+	}
+}
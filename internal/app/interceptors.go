@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddUnaryInterceptor appends a unary server interceptor after the built-in
+// recovery, logging, metrics and tracing interceptors. It must be called
+// before Run, since the chain is assembled the first time the gRPC server
+// handles a request.
+func (app *App) AddUnaryInterceptor(interceptor grpc.UnaryServerInterceptor) {
+	app.unaryInterceptors = append(app.unaryInterceptors, interceptor)
+}
+
+// AddStreamInterceptor appends a stream server interceptor, with the same
+// timing constraints as AddUnaryInterceptor.
+func (app *App) AddStreamInterceptor(interceptor grpc.StreamServerInterceptor) {
+	app.streamInterceptors = append(app.streamInterceptors, interceptor)
+}
+
+// unaryInterceptor is installed on the gRPC server at construction time. It
+// lazily assembles the full chain on first use so that services can still
+// call AddUnaryInterceptor after New returns but before the server starts
+// accepting requests.
+func (app *App) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	app.unaryChainOnce.Do(func() {
+		app.unaryChain = grpc_middleware.ChainUnaryServer(
+			append(app.defaultUnaryInterceptors(), app.unaryInterceptors...)...,
+		)
+	})
+	return app.unaryChain(ctx, req, info, handler)
+}
+
+// streamInterceptor mirrors unaryInterceptor for streaming RPCs.
+func (app *App) streamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	app.streamChainOnce.Do(func() {
+		app.streamChain = grpc_middleware.ChainStreamServer(
+			append(app.defaultStreamInterceptors(), app.streamInterceptors...)...,
+		)
+	})
+	return app.streamChain(srv, ss, info, handler)
+}
+
+func (app *App) defaultUnaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		grpc_recovery.UnaryServerInterceptor(grpc_recovery.WithRecoveryHandlerContext(app.recoverPanic)),
+		grpc_zap.UnaryServerInterceptor(app.logger),
+		grpc_prometheus.UnaryServerInterceptor,
+		otelgrpc.UnaryServerInterceptor(),
+	}
+}
+
+func (app *App) defaultStreamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		grpc_recovery.StreamServerInterceptor(grpc_recovery.WithRecoveryHandlerContext(app.recoverPanic)),
+		grpc_zap.StreamServerInterceptor(app.logger),
+		grpc_prometheus.StreamServerInterceptor,
+		otelgrpc.StreamServerInterceptor(),
+	}
+}
+
+// recoverPanic is the grpc_recovery panic handler: it logs the panic via the
+// application logger and turns it into a codes.Internal error rather than
+// letting it crash the process.
+func (app *App) recoverPanic(ctx context.Context, p interface{}) error {
+	app.Logger().Error("panic in GRPC handler", zap.Any("panic", p))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsCredentials loads server TLS credentials from Config. It returns nil,
+// nil when no certificate is configured, so the caller falls back to an
+// insecure server — suitable only for local development.
+func (cfg Config) tlsCredentials() (credentials.TransportCredentials, error) {
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load TLS certificate")
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.NoClientCert,
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		caCert, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read TLS client CA")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("could not parse TLS client CA")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// tlsClientCredentials builds the client-side credentials for the gateway's
+// loopback dial to the local GRPC server. It returns nil, nil when no
+// certificate is configured, so the caller falls back to insecure
+// credentials. Server verification is skipped because this dial never
+// leaves the process — there is no third party to authenticate — but when
+// mTLS is enabled the server's own certificate is presented as the client
+// certificate, since that's the only identity this process has. The
+// handshake then satisfies RequireAndVerifyClientCert only if that
+// certificate is signed by a CA in TLSClientCAFile.
+func (cfg Config) tlsClientCredentials() (credentials.TransportCredentials, error) {
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load TLS certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -10,3 +10,14 @@ type Score struct {
 	UpdatedAt time.Time `db:"updated_at"`
 	Neighbors []int64   `db:"neighbors"`
 }
+
+// OutboxEvent is a row in the outbox table, written in the same transaction
+// as the domain change it describes and later forwarded to an EventSink by
+// the outbox poller.
+type OutboxEvent struct {
+	ID        int64      `db:"id"`
+	Topic     string     `db:"topic"`
+	Payload   []byte     `db:"payload"`
+	CreatedAt time.Time  `db:"created_at"`
+	SentAt    *time.Time `db:"sent_at"`
+}
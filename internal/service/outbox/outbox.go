@@ -0,0 +1,77 @@
+// Package outbox forwards rows written via Repository.PublishEvent to a
+// pluggable EventSink, giving exactly-once-ish delivery for writes that must
+// be atomic with a side effect such as a message publish.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-service/internal/service/models"
+	"go-service/internal/service/repository"
+)
+
+// EventSink forwards a single outbox event to an external transport, e.g.
+// Kafka, NATS, or stdout for local development.
+type EventSink interface {
+	Send(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Poller periodically drains pending outbox rows into an EventSink. It satisfies
+// app.Component (Name/Start/Stop) so it can be registered with App.AddComponent
+// without the outbox package depending on the app package.
+type Poller struct {
+	repo      *repository.Repository
+	sink      EventSink
+	logger    *zap.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewPoller returns a Poller that checks repo for new events every interval
+// and sends up to batchSize of them to sink per check.
+func NewPoller(repo *repository.Repository, sink EventSink, logger *zap.Logger, interval time.Duration, batchSize int) *Poller {
+	return &Poller{repo: repo, sink: sink, logger: logger, interval: interval, batchSize: batchSize}
+}
+
+func (p *Poller) Name() string { return "outbox-poller" }
+
+func (p *Poller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := p.poll(ctx); err != nil {
+				p.logger.Error("outbox poll failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (p *Poller) Stop(context.Context) error {
+	return nil
+}
+
+func (p *Poller) poll(ctx context.Context) error {
+	events, err := p.repo.PendingEvents(ctx, p.batchSize)
+	if err != nil {
+		return fmt.Errorf("could not fetch pending events: %w", err)
+	}
+
+	for _, event := range events {
+		if err := p.sink.Send(ctx, event); err != nil {
+			return fmt.Errorf("could not send event %d: %w", event.ID, err)
+		}
+		if err := p.repo.MarkEventSent(ctx, event.ID); err != nil {
+			return fmt.Errorf("could not mark event %d sent: %w", event.ID, err)
+		}
+	}
+	return nil
+}
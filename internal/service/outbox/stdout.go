@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go-service/internal/service/models"
+)
+
+// StdoutSink logs outbox events instead of forwarding them to a real broker.
+// It's meant for local development when no Kafka/NATS cluster is available.
+type StdoutSink struct {
+	logger *zap.Logger
+}
+
+func NewStdoutSink(logger *zap.Logger) *StdoutSink {
+	return &StdoutSink{logger: logger}
+}
+
+func (s *StdoutSink) Send(ctx context.Context, event models.OutboxEvent) error {
+	s.logger.Info("outbox event",
+		zap.Int64("id", event.ID),
+		zap.String("topic", event.Topic),
+		zap.ByteString("payload", event.Payload),
+	)
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,14 +12,55 @@ import (
 	"go-service/internal/service/models"
 )
 
+// executor is the subset of *sqlx.DB and *sqlx.Tx that Repository methods
+// need. It lets a Repository run either against the pool directly or, once
+// rebound by WithTx, against a single transaction.
+type executor interface {
+	BindNamed(query string, arg interface{}) (string, []interface{}, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 type Repository struct {
-	db *sqlx.DB
+	db executor
 }
 
 func New(db *sqlx.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// WithTx runs fn against a Repository bound to a new transaction, committing
+// if fn returns nil and rolling back otherwise (including on panic). It
+// fails if r is already bound to a transaction, since nested transactions
+// aren't supported.
+func (r *Repository) WithTx(ctx context.Context, fn func(*Repository) error) (err error) {
+	db, ok := r.db.(*sqlx.DB)
+	if !ok {
+		return fmt.Errorf("WithTx: repository is already bound to a transaction")
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	err = fn(&Repository{db: tx})
+	return err
+}
+
 const addScoreSQL = `
 insert into scores (id, score, updated_at)
 values (:id, :score, :updated_at)
@@ -27,6 +70,8 @@ on conflict (id) do update set
 returning id, score, updated_at
 `
 
+// AddScore works whether r is bound to the pool or, via WithTx, to a
+// transaction — e.g. to compose it atomically with PublishEvent.
 func (r *Repository) AddScore(ctx context.Context, id int64, score float64) (*models.Score, error) {
 	result := new(models.Score)
 	query, args, err := r.db.BindNamed(addScoreSQL, map[string]interface{}{
@@ -42,3 +87,96 @@ func (r *Repository) AddScore(ctx context.Context, id int64, score float64) (*mo
 	}
 	return result, nil
 }
+
+// AddScoreAndPublish adds score to id and publishes the resulting row under
+// topic in the same transaction, so the event only reaches the outbox
+// poller if the score update commits.
+func (r *Repository) AddScoreAndPublish(ctx context.Context, id int64, score float64, topic string) (*models.Score, error) {
+	var result *models.Score
+	err := r.WithTx(ctx, func(txRepo *Repository) error {
+		var err error
+		result, err = txRepo.AddScore(ctx, id, score)
+		if err != nil {
+			return err
+		}
+		return txRepo.PublishEvent(ctx, topic, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// See schema.sql for the outbox table definition.
+const publishEventSQL = `
+insert into outbox (topic, payload, created_at)
+values (:topic, :payload, :created_at)
+`
+
+// PublishEvent writes payload as a JSON-encoded outbox row under topic. Call
+// it inside the same WithTx callback as the write it should be delivered
+// alongside, so the event is only published if that write commits.
+func (r *Repository) PublishEvent(ctx context.Context, topic string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not marshal event payload: %w", err)
+	}
+
+	query, args, err := r.db.BindNamed(publishEventSQL, map[string]interface{}{
+		"topic": topic,
+		// Bind as string, not []byte: lib/pq sends a []byte bind as bytea, and
+		// Postgres won't implicitly cast bytea to the outbox.payload jsonb
+		// column.
+		"payload":    string(body),
+		"created_at": time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not bind publishEventSQL: %w", err)
+	}
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not execute publishEventSQL: %w", err)
+	}
+	return nil
+}
+
+const pendingEventsSQL = `
+select id, topic, payload, created_at, sent_at
+from outbox
+where sent_at is null
+order by id
+limit :limit
+`
+
+// PendingEvents returns up to limit outbox rows that haven't been marked
+// sent yet, oldest first.
+func (r *Repository) PendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	query, args, err := r.db.BindNamed(pendingEventsSQL, map[string]interface{}{"limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("could not bind pendingEventsSQL: %w", err)
+	}
+	var events []models.OutboxEvent
+	if err = r.db.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, fmt.Errorf("could not execute pendingEventsSQL: %w", err)
+	}
+	return events, nil
+}
+
+const markEventSentSQL = `
+update outbox set sent_at = :sent_at where id = :id
+`
+
+// MarkEventSent records that the outbox row id was delivered, so later
+// PendingEvents calls skip it.
+func (r *Repository) MarkEventSent(ctx context.Context, id int64) error {
+	query, args, err := r.db.BindNamed(markEventSentSQL, map[string]interface{}{
+		"id":      id,
+		"sent_at": time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not bind markEventSentSQL: %w", err)
+	}
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not execute markEventSentSQL: %w", err)
+	}
+	return nil
+}
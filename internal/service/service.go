@@ -3,11 +3,17 @@ package service
 import (
 	"context"
 
+	"google.golang.org/grpc/codes"
+
 	"go-service/internal/app"
 	"go-service/internal/service/repository"
 	"go-service/pkg/service/server"
 )
 
+// scoreUpdatedTopic is the outbox topic AddScoreAndPublish writes Add's
+// result under, for the outbox poller to forward to the configured sink.
+const scoreUpdatedTopic = "score.updated"
+
 type service struct {
 	*app.App
 	server.UnimplementedServiceServer
@@ -22,5 +28,12 @@ func New(app *app.App) server.ServiceServer {
 }
 
 func (s *service) Add(ctx context.Context, request *server.AddRequest) (*server.AddResponse, error) {
-	return nil, nil
+	result, err := s.repo.AddScoreAndPublish(ctx, request.GetId(), request.GetScore(), scoreUpdatedTopic)
+	if err != nil {
+		return nil, s.Error(codes.Internal, err)
+	}
+	return &server.AddResponse{
+		Id:    result.ID,
+		Score: result.Score,
+	}, nil
 }